@@ -0,0 +1,171 @@
+package v1alpha1
+
+import (
+	operatorv1 "github.com/openshift/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProvisioningSingletonName is the name of the only acceptable Provisioning
+// resource. Any Provisioning resource that has a different name will be
+// ignored by the controller.
+const ProvisioningSingletonName = "provisioning-configuration"
+
+// ProvisioningNetwork is the type of network flow that is being used
+// to set up the bootstrap network.
+type ProvisioningNetwork string
+
+const (
+	// ProvisioningNetworkManaged indicates that metal3 should fully
+	// manage the provisioning network.
+	ProvisioningNetworkManaged ProvisioningNetwork = "Managed"
+
+	// ProvisioningNetworkUnmanaged indicates that metal3 should deploy
+	// the provisioning services (Ironic, etc.) but that DHCP/DNS for the
+	// provisioning network is managed out-of-band by the operator.
+	ProvisioningNetworkUnmanaged ProvisioningNetwork = "Unmanaged"
+
+	// ProvisioningNetworkDisabled indicates that metal3 should not
+	// create a provisioning network at all.
+	ProvisioningNetworkDisabled ProvisioningNetwork = "Disabled"
+)
+
+// ProvisioningPhase summarizes the overall state of the Provisioning
+// resource, in the same spirit as the Phase field used by machine
+// providers to report InstanceState.
+type ProvisioningPhase string
+
+const (
+	// PhasePending means CBO has not yet started reconciling the
+	// Provisioning CR.
+	PhasePending ProvisioningPhase = "Pending"
+
+	// PhaseProvisioning means CBO is actively creating or updating the
+	// metal3/Ironic objects required by the CR.
+	PhaseProvisioning ProvisioningPhase = "Provisioning"
+
+	// PhaseReady means all managed objects have been reconciled
+	// successfully and are available.
+	PhaseReady ProvisioningPhase = "Ready"
+
+	// PhaseDegraded means reconciling the metal3 objects for the CR
+	// failed, e.g. because its spec is invalid.
+	PhaseDegraded ProvisioningPhase = "Degraded"
+
+	// PhaseDisabled means the Provisioning CR's ProvisioningNetwork is
+	// Disabled and CBO is intentionally not managing any metal3 objects.
+	PhaseDisabled ProvisioningPhase = "Disabled"
+)
+
+const (
+	// AvailableCondition indicates that the objects CBO manages on
+	// behalf of the Provisioning CR are available for use.
+	AvailableCondition operatorv1.OperatorStatusType = "Available"
+
+	// ProgressingCondition indicates that CBO is actively working to
+	// reconcile the Provisioning CR's desired state.
+	ProgressingCondition operatorv1.OperatorStatusType = "Progressing"
+
+	// DegradedCondition indicates that the Provisioning CR is not in
+	// the state CBO expects and requires operator attention.
+	DegradedCondition operatorv1.OperatorStatusType = "Degraded"
+)
+
+// ProvisioningSpec defines the desired state of Provisioning.
+type ProvisioningSpec struct {
+	// ProvisioningInterface is the name of the network interface on a
+	// baremetal server to the provisioning network.
+	ProvisioningInterface string `json:"provisioningInterface,omitempty"`
+
+	// ProvisioningIP is the IP address assigned to the provisioning
+	// interface of the baremetal server.
+	ProvisioningIP string `json:"provisioningIP,omitempty"`
+
+	// ProvisioningNetwork selects how CBO should configure the
+	// provisioning network: Managed, Unmanaged, or Disabled.
+	ProvisioningNetwork ProvisioningNetwork `json:"provisioningNetwork,omitempty"`
+
+	// ProvisioningDHCPRange is the range of IP addresses to use for
+	// DHCP on the provisioning network, in the form "start,end". It is
+	// only honored when ProvisioningNetwork is Managed; it is invalid
+	// to set it when the provisioning network is Unmanaged or Disabled,
+	// since those modes don't run CBO's own dnsmasq instance.
+	// +optional
+	ProvisioningDHCPRange string `json:"provisioningDHCPRange,omitempty"`
+
+	// UnmanagedLeaseConfigMap names a ConfigMap, in CBO's own operator
+	// namespace (Provisioning is cluster-scoped, so there is no
+	// Provisioning namespace to match), whose data holds MAC address to
+	// IP address lease mappings maintained by an external DHCP server.
+	// Only honored when ProvisioningNetwork is Unmanaged.
+	// +optional
+	UnmanagedLeaseConfigMap string `json:"unmanagedLeaseConfigMap,omitempty"`
+
+	// UnmanagedLeaseFile is the path, as seen from CBO's own container, to
+	// a dhcpd.leases-style file maintained by an external DHCP server.
+	// Only honored when ProvisioningNetwork is Unmanaged.
+	// +optional
+	UnmanagedLeaseFile string `json:"unmanagedLeaseFile,omitempty"`
+
+	// WatchAllNamespaces indicates whether the Ironic and dnsmasq
+	// instances should watch for BareMetalHost resources in all
+	// namespaces or only in the provisioning namespace.
+	// +optional
+	WatchAllNamespaces bool `json:"watchAllNamespaces,omitempty"`
+}
+
+// ProvisioningStatus defines the observed state of Provisioning.
+type ProvisioningStatus struct {
+	operatorv1.OperatorStatus `json:",inline"`
+
+	// Phase is a high level summary of where the Provisioning resource
+	// is in its lifecycle. Consumers that only care about "is it done"
+	// should prefer the Available condition; Phase exists for quick
+	// human-readable status, e.g. via `oc get`.
+	// +optional
+	Phase ProvisioningPhase `json:"phase,omitempty"`
+
+	// UnmanagedLeaseHints lists the MAC/IP pairs most recently read
+	// from the Unmanaged provisioning network's external lease source.
+	// Consumers can use this list to pre-populate the bootMACAddress of
+	// a BareMetalHost before handing it off to Ironic, the same way the
+	// BPA-operator ties MAC addresses to cluster membership. It is only
+	// populated when ProvisioningNetwork is Unmanaged.
+	// +optional
+	UnmanagedLeaseHints []UnmanagedLeaseHint `json:"unmanagedLeaseHints,omitempty"`
+}
+
+// UnmanagedLeaseHint is a single MAC/IP pair surfaced from an
+// externally-managed DHCP lease source.
+type UnmanagedLeaseHint struct {
+	// MACAddress is the hardware ethernet address of the lease.
+	MACAddress string `json:"macAddress"`
+
+	// IPAddress is the address the external DHCP server leased to
+	// MACAddress.
+	IPAddress string `json:"ipAddress"`
+}
+
+// +kubebuilder:object:root=true
+
+// Provisioning contains configuration used by the Provisioning
+// service (Ironic) to provision baremetal hosts.
+type Provisioning struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProvisioningSpec   `json:"spec,omitempty"`
+	Status ProvisioningStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ProvisioningList contains a list of Provisioning.
+type ProvisioningList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Provisioning `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Provisioning{}, &ProvisioningList{})
+}
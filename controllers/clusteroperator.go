@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+// clusterOperatorName is the name CBO registers itself under in the
+// cluster's list of ClusterOperators.
+const clusterOperatorName = "baremetal"
+
+// conditionTypeMap translates our Provisioning conditions into the
+// equivalent configv1.ClusterStatusConditionType, so that external
+// consumers can wait on `oc get co baremetal` instead of polling our
+// deployments directly.
+var conditionTypeMap = map[operatorv1.OperatorStatusType]configv1.ClusterStatusConditionType{
+	metal3iov1alpha1.AvailableCondition:   configv1.OperatorAvailable,
+	metal3iov1alpha1.ProgressingCondition: configv1.OperatorProgressing,
+	metal3iov1alpha1.DegradedCondition:    configv1.OperatorDegraded,
+}
+
+// syncClusterOperatorStatus mirrors the Provisioning CR's conditions onto
+// the "baremetal" ClusterOperator so that Available=True (the Ready
+// phase) can be observed the same way as any other cluster capability.
+func (r *ProvisioningReconciler) syncClusterOperatorStatus(ctx context.Context, config *metal3iov1alpha1.Provisioning) error {
+	co, err := r.OSClient.ConfigV1().ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		co = &configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterOperatorName},
+		}
+		co, err = r.OSClient.ConfigV1().ClusterOperators().Create(ctx, co, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get or create ClusterOperator %q: %w", clusterOperatorName, err)
+	}
+
+	now := metav1.Now()
+	for _, cond := range config.Status.Conditions {
+		coType, ok := conditionTypeMap[cond.Type]
+		if !ok {
+			continue
+		}
+		setClusterOperatorCondition(co, coType, configv1.ConditionStatus(cond.Status), cond.Reason, cond.Message, now)
+	}
+
+	_, err = r.OSClient.ConfigV1().ClusterOperators().UpdateStatus(ctx, co, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update ClusterOperator %q status: %w", clusterOperatorName, err)
+	}
+	return nil
+}
+
+func setClusterOperatorCondition(co *configv1.ClusterOperator, condType configv1.ClusterStatusConditionType, status configv1.ConditionStatus, reason, message string, now metav1.Time) {
+	for i := range co.Status.Conditions {
+		cond := &co.Status.Conditions[i]
+		if cond.Type != condType {
+			continue
+		}
+		if cond.Status != status {
+			cond.Status = status
+			cond.LastTransitionTime = now
+		}
+		cond.Reason = reason
+		cond.Message = message
+		return
+	}
+	co.Status.Conditions = append(co.Status.Conditions, configv1.ClusterOperatorStatusCondition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
@@ -0,0 +1,344 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	configv1 "github.com/openshift/api/config/v1"
+	osclientset "github.com/openshift/client-go/config/clientset/versioned"
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+	"github.com/openshift/cluster-baremetal-operator/provisioning"
+)
+
+// provisioningFinalizer holds the Provisioning CR until the reconciler
+// has seen its deletion; see finalizeProvisioning for what is done then.
+const provisioningFinalizer = "provisioning.metal3.io/finalizer"
+
+// componentNamespace is the namespace CBO itself runs in. Provisioning
+// is a cluster-scoped singleton, so it has no namespace of its own for
+// UnmanagedLeaseConfigMap to share; the ConfigMap is looked up here
+// instead.
+const componentNamespace = "openshift-machine-api"
+
+// podNameEnvVar and podNamespaceEnvVar are populated by the downward
+// API in CBO's own Deployment, and identify the Pod to read
+// status.podIPs from.
+const (
+	podNameEnvVar      = "POD_NAME"
+	podNamespaceEnvVar = "POD_NAMESPACE"
+)
+
+// ProvisioningReconciler reconciles a Provisioning object
+type ProvisioningReconciler struct {
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	OSClient osclientset.Interface
+}
+
+// Reconcile reads the Provisioning singleton, and if the baremetal
+// platform is enabled, reconciles the metal3/Ironic stack to match it.
+func (r *ProvisioningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	enabled, err := r.isEnabled()
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("could not determine whether to run: %w", err)
+	}
+	if !enabled {
+		return ctrl.Result{}, nil
+	}
+
+	baremetalConfig, err := r.readProvisioningCR(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if baremetalConfig == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if !baremetalConfig.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalizeProvisioning(ctx, baremetalConfig)
+	}
+
+	if baremetalConfig.Status.Phase == "" {
+		if err := r.updateProvisioningStatus(ctx, baremetalConfig, metal3iov1alpha1.PhasePending, "NewProvisioningCR", "waiting to reconcile the Provisioning CR"); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if !controllerutil.ContainsFinalizer(baremetalConfig, provisioningFinalizer) {
+		controllerutil.AddFinalizer(baremetalConfig, provisioningFinalizer)
+		if err := r.Client.Update(ctx, baremetalConfig); err != nil {
+			return ctrl.Result{}, fmt.Errorf("unable to add finalizer to Provisioning CR: %w", err)
+		}
+	}
+
+	if baremetalConfig.Spec.ProvisioningNetwork == metal3iov1alpha1.ProvisioningNetworkDisabled {
+		if err := r.updateProvisioningStatus(ctx, baremetalConfig, metal3iov1alpha1.PhaseDisabled, "ProvisioningNetworkDisabled", "provisioning network is disabled"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, r.syncClusterOperatorStatus(ctx, baremetalConfig)
+	}
+
+	if err := r.updateProvisioningStatus(ctx, baremetalConfig, metal3iov1alpha1.PhaseProvisioning, "ReconcilingObjects", "reconciling metal3 objects"); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.ensureMetal3Objects(ctx, baremetalConfig); err != nil {
+		if statusErr := r.updateProvisioningStatus(ctx, baremetalConfig, metal3iov1alpha1.PhaseDegraded, "ReconcileError", err.Error()); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		_ = r.syncClusterOperatorStatus(ctx, baremetalConfig)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateProvisioningStatus(ctx, baremetalConfig, metal3iov1alpha1.PhaseReady, "AsExpected", "metal3 objects are available"); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.syncClusterOperatorStatus(ctx, baremetalConfig)
+}
+
+// finalizeProvisioning drops our finalizer so deletion can complete.
+// It deletes no objects itself; in particular the "baremetal"
+// ClusterOperator created by syncClusterOperatorStatus is left in place.
+func (r *ProvisioningReconciler) finalizeProvisioning(ctx context.Context, config *metal3iov1alpha1.Provisioning) error {
+	if !controllerutil.ContainsFinalizer(config, provisioningFinalizer) {
+		return nil
+	}
+	controllerutil.RemoveFinalizer(config, provisioningFinalizer)
+	if err := r.Client.Update(ctx, config); err != nil {
+		return fmt.Errorf("unable to remove finalizer from Provisioning CR: %w", err)
+	}
+	return nil
+}
+
+// ensureMetal3Objects validates the spec, refreshes the Unmanaged lease
+// hints on the CR's in-memory status, and builds the metal3/Ironic
+// container set for the detected network stack. It does not create or
+// update any Deployment, Service, or other cluster object.
+func (r *ProvisioningReconciler) ensureMetal3Objects(ctx context.Context, config *metal3iov1alpha1.Provisioning) error {
+	if err := provisioning.ValidateUnmanagedConfig(config.Spec); err != nil {
+		return fmt.Errorf("invalid Unmanaged provisioning network config: %w", err)
+	}
+
+	if config.Spec.ProvisioningNetwork == metal3iov1alpha1.ProvisioningNetworkUnmanaged {
+		leases, err := r.readUnmanagedLeases(ctx, config)
+		if err != nil {
+			return err
+		}
+		config.Status.UnmanagedLeaseHints = leaseHints(leases)
+	} else {
+		config.Status.UnmanagedLeaseHints = nil
+	}
+
+	stack, err := r.podNetworkStack(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The container set built here (ironic, plus dnsmasq only in
+	// Managed mode) is what would be applied to the metal3 Deployment;
+	// building and applying the Deployment itself is out of scope for
+	// this reconciler sketch.
+	_ = provisioning.IronicAndDnsmasqContainers(config.Spec, stack)
+
+	return nil
+}
+
+// podNetworkStack determines the network stack - v4, v6, or dual - that
+// Ironic should listen on, from the CBO pod's own status.podIPs. When
+// POD_NAME/POD_NAMESPACE aren't set, such as when running outside a
+// cluster, it falls back to NetworkStackV4 rather than failing
+// reconciliation over a detection feature.
+func (r *ProvisioningReconciler) podNetworkStack(ctx context.Context) (provisioning.NetworkStackType, error) {
+	name := os.Getenv(podNameEnvVar)
+	namespace := os.Getenv(podNamespaceEnvVar)
+	if name == "" || namespace == "" {
+		return provisioning.NetworkStackV4, nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, pod); err != nil {
+		return provisioning.NetworkStackV4, fmt.Errorf("unable to read own Pod %s/%s to determine the network stack: %w", namespace, name, err)
+	}
+	return r.reconcilerNetworkStack(pod.Status.PodIPs), nil
+}
+
+// readUnmanagedLeases loads the externally-managed MAC/IP lease data
+// for Unmanaged mode, from either the configured ConfigMap or host-path
+// lease file, so it can be used to pre-populate BareMetalHost resources.
+func (r *ProvisioningReconciler) readUnmanagedLeases(ctx context.Context, config *metal3iov1alpha1.Provisioning) ([]provisioning.MACIPLease, error) {
+	if name := config.Spec.UnmanagedLeaseConfigMap; name != "" {
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: componentNamespace}, cm); err != nil {
+			return nil, fmt.Errorf("unable to read unmanaged lease ConfigMap %q: %w", name, err)
+		}
+		return provisioning.ParseLeaseConfigMapData(cm.Data), nil
+	}
+
+	contents, err := readLeaseFile(config.Spec.UnmanagedLeaseFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read unmanaged lease file %q: %w", config.Spec.UnmanagedLeaseFile, err)
+	}
+	return provisioning.ParseDHCPDLeases(contents)
+}
+
+// leaseHints converts the leases read from the external DHCP source
+// into the status-level hints that pre-populate a BareMetalHost's
+// bootMACAddress/IP before Ironic takes it over.
+func leaseHints(leases []provisioning.MACIPLease) []metal3iov1alpha1.UnmanagedLeaseHint {
+	hints := make([]metal3iov1alpha1.UnmanagedLeaseHint, 0, len(leases))
+	for _, lease := range leases {
+		hints = append(hints, metal3iov1alpha1.UnmanagedLeaseHint{
+			MACAddress: lease.MACAddress,
+			IPAddress:  lease.IPAddress,
+		})
+	}
+	return hints
+}
+
+// readLeaseFile is a variable, rather than a direct os.ReadFile call,
+// so tests can substitute lease contents without requiring a real
+// host-path mount.
+var readLeaseFile = func(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// isEnabled reports whether the cluster's infrastructure platform is
+// BareMetal, which is the only platform CBO acts on.
+func (r *ProvisioningReconciler) isEnabled() (bool, error) {
+	infra, err := r.OSClient.ConfigV1().Infrastructures().Get(context.Background(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("unable to determine Infrastructure platform: %w", err)
+	}
+	if infra.Status.Platform == "" {
+		return false, nil
+	}
+	return infra.Status.Platform == configv1.BareMetalPlatformType, nil
+}
+
+// readProvisioningCR reads the Provisioning singleton. It returns a nil
+// config, without error, if the singleton does not exist.
+func (r *ProvisioningReconciler) readProvisioningCR(ctx context.Context) (*metal3iov1alpha1.Provisioning, error) {
+	baremetalConfig := &metal3iov1alpha1.Provisioning{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: metal3iov1alpha1.ProvisioningSingletonName}, baremetalConfig); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read Provisioning CR: %w", err)
+	}
+	return baremetalConfig, nil
+}
+
+// defaultPortForScheme returns the port implied by a URL scheme when
+// the URL itself doesn't specify one, mirroring what an HTTP client
+// would dial.
+func defaultPortForScheme(scheme string) string {
+	switch scheme {
+	case "http":
+		return "80"
+	default:
+		return "443"
+	}
+}
+
+// apiServerInternalEndpoint returns the scheme, host, and port of the
+// cluster's internal API server URL, as reported on the Infrastructure
+// CR. IPv6-literal hosts such as "https://[fd00::1]:6443" have their
+// brackets stripped from host, and a missing port is filled in from
+// defaultPortForScheme so callers never need to special-case it.
+func (r *ProvisioningReconciler) apiServerInternalEndpoint(ctx context.Context) (scheme, host, port string, err error) {
+	infra, err := r.OSClient.ConfigV1().Infrastructures().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return "", "", "", fmt.Errorf("unable to determine API server internal host: %w", err)
+	}
+	u, err := url.Parse(infra.Status.APIServerInternalURL)
+	if err != nil {
+		return "", "", "", fmt.Errorf("unable to parse APIServerInternalURL %q: %w", infra.Status.APIServerInternalURL, err)
+	}
+
+	scheme = u.Scheme
+	host = u.Hostname()
+	port = u.Port()
+	if port == "" {
+		port = defaultPortForScheme(scheme)
+	}
+	return scheme, host, port, nil
+}
+
+// apiServerInternalHost returns the hostname portion of the cluster's
+// internal API server URL, as reported on the Infrastructure CR.
+func (r *ProvisioningReconciler) apiServerInternalHost(ctx context.Context) (string, error) {
+	_, host, _, err := r.apiServerInternalEndpoint(ctx)
+	return host, err
+}
+
+// networkStack inspects the IP addresses assigned to the CBO pod and
+// determines whether the provisioning network should be treated as
+// IPv4-only, IPv6-only, or dual-stack.
+func networkStack(ips []net.IP) provisioning.NetworkStackType {
+	var haveV4, haveV6 bool
+	for _, ip := range ips {
+		if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+			continue
+		}
+		if ip.To4() != nil {
+			haveV4 = true
+		} else {
+			haveV6 = true
+		}
+	}
+	switch {
+	case haveV4 && haveV6:
+		return provisioning.NetworkStackDual
+	case haveV6:
+		return provisioning.NetworkStackV6
+	default:
+		return provisioning.NetworkStackV4
+	}
+}
+
+// reconcilerNetworkStack determines the provisioning network stack from
+// the CBO pod's status.podIPs, as reported through the downward API,
+// rather than from locally-enumerated network interfaces.
+func (r *ProvisioningReconciler) reconcilerNetworkStack(podIPs []corev1.PodIP) provisioning.NetworkStackType {
+	return provisioning.NetworkStackFromPodIPs(podIPs)
+}
+
+// SetupWithManager sets up the controller with the Manager. ConfigMaps
+// are watched, rather than owned, because an Unmanaged lease ConfigMap
+// lives alongside the Provisioning singleton rather than being created
+// by this controller; any change to one re-queues the singleton.
+func (r *ProvisioningReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&metal3iov1alpha1.Provisioning{}).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.leaseConfigMapToProvisioningRequest)).
+		Complete(r)
+}
+
+// leaseConfigMapToProvisioningRequest re-queues the Provisioning
+// singleton whenever the ConfigMap it names as an Unmanaged lease
+// source changes.
+func (r *ProvisioningReconciler) leaseConfigMapToProvisioningRequest(ctx context.Context, obj client.Object) []ctrl.Request {
+	config, err := r.readProvisioningCR(ctx)
+	if err != nil || config == nil || obj.GetNamespace() != componentNamespace || config.Spec.UnmanagedLeaseConfigMap != obj.GetName() {
+		return nil
+	}
+	return []ctrl.Request{{NamespacedName: types.NamespacedName{Name: config.Name}}}
+}
@@ -7,30 +7,26 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	fakeconfigclientset "github.com/openshift/client-go/config/clientset/versioned/fake"
 	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+	"github.com/openshift/cluster-baremetal-operator/pkg/scheme"
+	"github.com/openshift/cluster-baremetal-operator/pkg/testutil"
 	"github.com/openshift/cluster-baremetal-operator/provisioning"
 )
 
-func setUpSchemeForReconciler() *runtime.Scheme {
-	scheme := runtime.NewScheme()
-	// we need to add the openshift/api to the scheme to be able to read
-	// the infrastructure CR
-	utilruntime.Must(configv1.AddToScheme(scheme))
-	utilruntime.Must(metal3iov1alpha1.AddToScheme(scheme))
-	return scheme
-}
-
-func newFakeProvisioningReconciler(scheme *runtime.Scheme, object runtime.Object) *ProvisioningReconciler {
+func newFakeProvisioningReconciler(objs ...client.Object) *ProvisioningReconciler {
 	return &ProvisioningReconciler{
-		Client:   fakeclient.NewFakeClientWithScheme(scheme, object),
-		Scheme:   scheme,
+		Client:   testutil.NewFakeClientBuilder(objs...),
+		Scheme:   scheme.Scheme,
 		OSClient: fakeconfigclientset.NewSimpleClientset(),
 	}
 }
@@ -136,7 +132,7 @@ func TestProvisioning(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Logf("Testing tc : %s", tc.name)
 
-			reconciler := newFakeProvisioningReconciler(setUpSchemeForReconciler(), tc.baremetalCR)
+			reconciler := newFakeProvisioningReconciler(tc.baremetalCR)
 			baremetalconfig, err := reconciler.readProvisioningCR(context.TODO())
 			if !tc.expectedError && err != nil {
 				t.Errorf("unexpected error: %v", err)
@@ -180,6 +176,21 @@ func TestNetworkStack(t *testing.T) {
 			ips:  []net.IP{net.ParseIP("2001:db8::68"), net.ParseIP("127.0.0.1")},
 			want: provisioning.NetworkStackV6,
 		},
+		{
+			name: "v4: with v6 link-local",
+			ips:  []net.IP{net.ParseIP("192.168.0.1"), net.ParseIP("fe80::1")},
+			want: provisioning.NetworkStackV4,
+		},
+		{
+			name: "dual: with v4 and v6 link-local mixed in",
+			ips: []net.IP{
+				net.ParseIP("169.254.0.5"),
+				net.ParseIP("fe80::1"),
+				net.ParseIP("192.168.0.1"),
+				net.ParseIP("2001:db8::68"),
+			},
+			want: provisioning.NetworkStackDual,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -191,6 +202,48 @@ func TestNetworkStack(t *testing.T) {
 	}
 }
 
+func TestNetworkStackFromPodIPs(t *testing.T) {
+	tests := []struct {
+		name   string
+		podIPs []corev1.PodIP
+		want   provisioning.NetworkStackType
+	}{
+		{
+			name:   "v4 only",
+			podIPs: []corev1.PodIP{{IP: "10.128.0.5"}},
+			want:   provisioning.NetworkStackV4,
+		},
+		{
+			name:   "v6 only",
+			podIPs: []corev1.PodIP{{IP: "2001:db8::5"}},
+			want:   provisioning.NetworkStackV6,
+		},
+		{
+			name:   "dual stack",
+			podIPs: []corev1.PodIP{{IP: "10.128.0.5"}, {IP: "2001:db8::5"}},
+			want:   provisioning.NetworkStackDual,
+		},
+		{
+			name: "ignores link-local and loopback and ULA",
+			podIPs: []corev1.PodIP{
+				{IP: "10.128.0.5"},
+				{IP: "169.254.169.254"},
+				{IP: "fe80::1"},
+				{IP: "127.0.0.1"},
+				{IP: "fc00::1"},
+			},
+			want: provisioning.NetworkStackV4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ProvisioningReconciler{}
+			got := r.reconcilerNetworkStack(tt.podIPs)
+			assert.Equal(t, tt.want, got, "network stack from pod IPs did not match")
+		})
+	}
+}
+
 func TestAPIServerInternalHost(t *testing.T) {
 	infra := &configv1.Infrastructure{
 		TypeMeta: metav1.TypeMeta{
@@ -207,7 +260,7 @@ func TestAPIServerInternalHost(t *testing.T) {
 	want := "api-int.ostest.test.metalkube.org"
 
 	r := &ProvisioningReconciler{
-		Scheme:   setUpSchemeForReconciler(),
+		Scheme:   scheme.Scheme,
 		OSClient: fakeconfigclientset.NewSimpleClientset(infra),
 	}
 	got, err := r.apiServerInternalHost(context.TODO())
@@ -219,3 +272,506 @@ func TestAPIServerInternalHost(t *testing.T) {
 		t.Errorf("ProvisioningReconciler.apiServerInternalHost() = %v, want %v", got, want)
 	}
 }
+
+func TestAPIServerInternalEndpoint(t *testing.T) {
+	testCases := []struct {
+		name       string
+		url        string
+		wantScheme string
+		wantHost   string
+		wantPort   string
+	}{
+		{
+			name:       "hostname with port",
+			url:        "https://api-int.ostest.test.metalkube.org:6443",
+			wantScheme: "https",
+			wantHost:   "api-int.ostest.test.metalkube.org",
+			wantPort:   "6443",
+		},
+		{
+			name:       "hostname without port",
+			url:        "https://api-int.ostest.test.metalkube.org",
+			wantScheme: "https",
+			wantHost:   "api-int.ostest.test.metalkube.org",
+			wantPort:   "443",
+		},
+		{
+			name:       "IPv6 literal with port",
+			url:        "https://[fd00::1]:6443",
+			wantScheme: "https",
+			wantHost:   "fd00::1",
+			wantPort:   "6443",
+		},
+		{
+			name:       "IPv6 literal without port",
+			url:        "https://[fd00::1]",
+			wantScheme: "https",
+			wantHost:   "fd00::1",
+			wantPort:   "443",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			infra := &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{APIServerInternalURL: tc.url},
+			}
+			r := &ProvisioningReconciler{
+				Scheme:   scheme.Scheme,
+				OSClient: fakeconfigclientset.NewSimpleClientset(infra),
+			}
+
+			gotScheme, gotHost, gotPort, err := r.apiServerInternalEndpoint(context.TODO())
+			if err != nil {
+				t.Fatalf("ProvisioningReconciler.apiServerInternalEndpoint() error = %v", err)
+			}
+			assert.Equal(t, tc.wantScheme, gotScheme, "scheme did not match")
+			assert.Equal(t, tc.wantHost, gotHost, "host did not match")
+			assert.Equal(t, tc.wantPort, gotPort, "port did not match")
+		})
+	}
+}
+
+func TestSetConditionsForPhase(t *testing.T) {
+	testCases := []struct {
+		name                string
+		phase               metal3iov1alpha1.ProvisioningPhase
+		expectedAvailable   operatorv1.ConditionStatus
+		expectedProgressing operatorv1.ConditionStatus
+		expectedDegraded    operatorv1.ConditionStatus
+	}{
+		{
+			name:                "Pending",
+			phase:               metal3iov1alpha1.PhasePending,
+			expectedAvailable:   operatorv1.ConditionFalse,
+			expectedProgressing: operatorv1.ConditionTrue,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "Provisioning",
+			phase:               metal3iov1alpha1.PhaseProvisioning,
+			expectedAvailable:   operatorv1.ConditionFalse,
+			expectedProgressing: operatorv1.ConditionTrue,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "Ready",
+			phase:               metal3iov1alpha1.PhaseReady,
+			expectedAvailable:   operatorv1.ConditionTrue,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "Degraded",
+			phase:               metal3iov1alpha1.PhaseDegraded,
+			expectedAvailable:   operatorv1.ConditionFalse,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionTrue,
+		},
+		{
+			name:                "Disabled",
+			phase:               metal3iov1alpha1.PhaseDisabled,
+			expectedAvailable:   operatorv1.ConditionTrue,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &metal3iov1alpha1.Provisioning{}
+			setConditionsForPhase(config, tc.phase, "TestReason", "test message")
+
+			assert.Equal(t, tc.phase, config.Status.Phase, "phase did not match")
+			assertConditionStatus(t, config, metal3iov1alpha1.AvailableCondition, tc.expectedAvailable)
+			assertConditionStatus(t, config, metal3iov1alpha1.ProgressingCondition, tc.expectedProgressing)
+			assertConditionStatus(t, config, metal3iov1alpha1.DegradedCondition, tc.expectedDegraded)
+		})
+	}
+}
+
+func assertConditionStatus(t *testing.T, config *metal3iov1alpha1.Provisioning, condType operatorv1.OperatorStatusType, want operatorv1.ConditionStatus) {
+	t.Helper()
+	for _, cond := range config.Status.Conditions {
+		if cond.Type == condType {
+			assert.Equal(t, want, cond.Status, "condition %s status did not match", condType)
+			return
+		}
+	}
+	t.Errorf("condition %s not found", condType)
+}
+
+func TestReconcileStatusTransitions(t *testing.T) {
+	testCases := []struct {
+		name                string
+		spec                metal3iov1alpha1.ProvisioningSpec
+		expectedPhase       metal3iov1alpha1.ProvisioningPhase
+		expectedAvailable   operatorv1.ConditionStatus
+		expectedProgressing operatorv1.ConditionStatus
+		expectedDegraded    operatorv1.ConditionStatus
+	}{
+		{
+			name:                "Managed network reconciles to Ready",
+			spec:                metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged},
+			expectedPhase:       metal3iov1alpha1.PhaseReady,
+			expectedAvailable:   operatorv1.ConditionTrue,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+		{
+			name:                "Invalid Unmanaged config reconciles to Degraded",
+			spec:                metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkUnmanaged},
+			expectedPhase:       metal3iov1alpha1.PhaseDegraded,
+			expectedAvailable:   operatorv1.ConditionFalse,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionTrue,
+		},
+		{
+			name:                "Disabled network reconciles to Disabled",
+			spec:                metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkDisabled},
+			expectedPhase:       metal3iov1alpha1.PhaseDisabled,
+			expectedAvailable:   operatorv1.ConditionTrue,
+			expectedProgressing: operatorv1.ConditionFalse,
+			expectedDegraded:    operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &metal3iov1alpha1.Provisioning{
+				ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+				Spec:       tc.spec,
+			}
+			infra := &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{Platform: configv1.BareMetalPlatformType},
+			}
+
+			reconciler := newFakeProvisioningReconciler(config)
+			reconciler.OSClient = fakeconfigclientset.NewSimpleClientset(infra)
+
+			req := ctrl.Request{NamespacedName: client.ObjectKey{Name: metal3iov1alpha1.ProvisioningSingletonName}}
+			if _, err := reconciler.Reconcile(context.TODO(), req); err != nil && tc.expectedPhase != metal3iov1alpha1.PhaseDegraded {
+				t.Fatalf("unexpected error reconciling: %v", err)
+			}
+
+			got := &metal3iov1alpha1.Provisioning{}
+			if err := reconciler.Client.Get(context.TODO(), client.ObjectKey{Name: metal3iov1alpha1.ProvisioningSingletonName}, got); err != nil {
+				t.Fatalf("unexpected error reading Provisioning CR: %v", err)
+			}
+			assert.Equal(t, tc.expectedPhase, got.Status.Phase, "phase did not match")
+			assertConditionStatus(t, got, metal3iov1alpha1.AvailableCondition, tc.expectedAvailable)
+			assertConditionStatus(t, got, metal3iov1alpha1.ProgressingCondition, tc.expectedProgressing)
+			assertConditionStatus(t, got, metal3iov1alpha1.DegradedCondition, tc.expectedDegraded)
+
+			co, err := reconciler.OSClient.ConfigV1().ClusterOperators().Get(context.TODO(), clusterOperatorName, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error reading ClusterOperator: %v", err)
+			}
+			assertClusterOperatorCondition(t, co, configv1.OperatorAvailable, configv1.ConditionStatus(tc.expectedAvailable))
+			assertClusterOperatorCondition(t, co, configv1.OperatorProgressing, configv1.ConditionStatus(tc.expectedProgressing))
+			assertClusterOperatorCondition(t, co, configv1.OperatorDegraded, configv1.ConditionStatus(tc.expectedDegraded))
+		})
+	}
+}
+
+func assertClusterOperatorCondition(t *testing.T, co *configv1.ClusterOperator, condType configv1.ClusterStatusConditionType, want configv1.ConditionStatus) {
+	t.Helper()
+	for _, cond := range co.Status.Conditions {
+		if cond.Type == condType {
+			assert.Equal(t, want, cond.Status, "ClusterOperator condition %s status did not match", condType)
+			return
+		}
+	}
+	t.Errorf("ClusterOperator condition %s not found", condType)
+}
+
+func TestIronicAndDnsmasqContainers(t *testing.T) {
+	testCases := []struct {
+		name             string
+		spec             metal3iov1alpha1.ProvisioningSpec
+		expectDnsmasq    bool
+		expectDHCPRange  bool
+		expectDHCPExtern bool
+	}{
+		{
+			name: "Managed",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:   metal3iov1alpha1.ProvisioningNetworkManaged,
+				ProvisioningDHCPRange: "172.22.0.10,172.22.0.100",
+			},
+			expectDnsmasq:   true,
+			expectDHCPRange: true,
+		},
+		{
+			name: "Unmanaged",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+				UnmanagedLeaseConfigMap: "dhcp-leases",
+			},
+			expectDnsmasq:    false,
+			expectDHCPExtern: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			containers := provisioning.IronicAndDnsmasqContainers(tc.spec, provisioning.NetworkStackV4)
+
+			var haveDnsmasq bool
+			for _, c := range containers {
+				if c.Name == "metal3-dnsmasq" {
+					haveDnsmasq = true
+					if tc.expectDHCPRange {
+						assert.Contains(t, c.Args, "--dhcp-range")
+					}
+				}
+			}
+			assert.Equal(t, tc.expectDnsmasq, haveDnsmasq, "dnsmasq container presence did not match")
+
+			ironic := containers[0]
+			assert.Equal(t, tc.expectDHCPExtern, contains(ironic.Args, "--dhcp-external"), "--dhcp-external flag did not match")
+		})
+	}
+}
+
+func contains(args []string, want string) bool {
+	for _, a := range args {
+		if a == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateUnmanagedConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		spec        metal3iov1alpha1.ProvisioningSpec
+		expectError bool
+	}{
+		{
+			name: "Managed is always valid",
+			spec: metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged},
+		},
+		{
+			name: "Unmanaged with ConfigMap",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+				UnmanagedLeaseConfigMap: "dhcp-leases",
+			},
+		},
+		{
+			name: "Unmanaged with lease file",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+				UnmanagedLeaseFile:  "/var/lib/dhcp/dhcpd.leases",
+			},
+		},
+		{
+			name:        "Unmanaged with no lease source",
+			spec:        metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkUnmanaged},
+			expectError: true,
+		},
+		{
+			name: "Unmanaged with both lease sources",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+				UnmanagedLeaseConfigMap: "dhcp-leases",
+				UnmanagedLeaseFile:      "/var/lib/dhcp/dhcpd.leases",
+			},
+			expectError: true,
+		},
+		{
+			name: "Unmanaged with DHCP range set",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+				UnmanagedLeaseConfigMap: "dhcp-leases",
+				ProvisioningDHCPRange:   "172.22.0.10,172.22.0.100",
+			},
+			expectError: true,
+		},
+		{
+			name: "Disabled with DHCP range set",
+			spec: metal3iov1alpha1.ProvisioningSpec{
+				ProvisioningNetwork:   metal3iov1alpha1.ProvisioningNetworkDisabled,
+				ProvisioningDHCPRange: "172.22.0.10,172.22.0.100",
+			},
+			expectError: true,
+		},
+		{
+			name:        "Disabled without DHCP range is valid",
+			spec:        metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkDisabled},
+			expectError: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := provisioning.ValidateUnmanagedConfig(tc.spec)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEnsureMetal3ObjectsSurfacesUnmanagedLeaseHints(t *testing.T) {
+	leaseConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "dhcp-leases", Namespace: componentNamespace},
+		Data: map[string]string{
+			"52:54:00:aa:bb:cc": "192.168.0.10",
+		},
+	}
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+		Spec: metal3iov1alpha1.ProvisioningSpec{
+			ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+			UnmanagedLeaseConfigMap: "dhcp-leases",
+		},
+	}
+	reconciler := newFakeProvisioningReconciler(config, leaseConfigMap)
+
+	err := reconciler.ensureMetal3Objects(context.TODO(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []metal3iov1alpha1.UnmanagedLeaseHint{{MACAddress: "52:54:00:aa:bb:cc", IPAddress: "192.168.0.10"}}
+	assert.Equal(t, want, config.Status.UnmanagedLeaseHints, "unmanaged lease hints did not match")
+}
+
+func TestEnsureMetal3ObjectsClearsStaleUnmanagedLeaseHints(t *testing.T) {
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+		Spec:       metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged},
+		Status: metal3iov1alpha1.ProvisioningStatus{
+			UnmanagedLeaseHints: []metal3iov1alpha1.UnmanagedLeaseHint{{MACAddress: "52:54:00:aa:bb:cc", IPAddress: "192.168.0.10"}},
+		},
+	}
+	reconciler := newFakeProvisioningReconciler(config)
+
+	err := reconciler.ensureMetal3Objects(context.TODO(), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assert.Nil(t, config.Status.UnmanagedLeaseHints, "stale unmanaged lease hints should be cleared once the mode is no longer Unmanaged")
+}
+
+func TestPodNetworkStack(t *testing.T) {
+	t.Run("falls back to v4 when POD_NAME/POD_NAMESPACE are unset", func(t *testing.T) {
+		reconciler := newFakeProvisioningReconciler()
+
+		got, err := reconciler.podNetworkStack(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, provisioning.NetworkStackV4, got)
+	})
+
+	t.Run("reads the stack from the CBO pod's status.podIPs", func(t *testing.T) {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-baremetal-operator-abc123", Namespace: componentNamespace},
+			Status: corev1.PodStatus{
+				PodIPs: []corev1.PodIP{{IP: "192.168.0.10"}, {IP: "2001:db8::1"}},
+			},
+		}
+		reconciler := newFakeProvisioningReconciler(pod)
+		t.Setenv(podNameEnvVar, pod.Name)
+		t.Setenv(podNamespaceEnvVar, pod.Namespace)
+
+		got, err := reconciler.podNetworkStack(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, provisioning.NetworkStackDual, got)
+	})
+}
+
+func TestPodNetworkStackDrivesIronicIPFamily(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-baremetal-operator-abc123", Namespace: componentNamespace},
+		Status: corev1.PodStatus{
+			PodIPs: []corev1.PodIP{{IP: "192.168.0.10"}, {IP: "2001:db8::1"}},
+		},
+	}
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+		Spec:       metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged},
+	}
+	reconciler := newFakeProvisioningReconciler(config, pod)
+	t.Setenv(podNameEnvVar, pod.Name)
+	t.Setenv(podNamespaceEnvVar, pod.Namespace)
+
+	stack, err := reconciler.podNetworkStack(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	containers := provisioning.IronicAndDnsmasqContainers(config.Spec, stack)
+	assert.Contains(t, containers[0].Args, "dual", "ironic container args did not reflect the detected dual-stack network")
+}
+
+func TestLeaseConfigMapToProvisioningRequest(t *testing.T) {
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+		Spec: metal3iov1alpha1.ProvisioningSpec{
+			ProvisioningNetwork:     metal3iov1alpha1.ProvisioningNetworkUnmanaged,
+			UnmanagedLeaseConfigMap: "dhcp-leases",
+		},
+	}
+	reconciler := newFakeProvisioningReconciler(config)
+
+	testCases := []struct {
+		name          string
+		configMapName string
+		namespace     string
+		expectRequest bool
+	}{
+		{name: "matching lease ConfigMap", configMapName: "dhcp-leases", namespace: componentNamespace, expectRequest: true},
+		{name: "unrelated ConfigMap", configMapName: "some-other-configmap", namespace: componentNamespace, expectRequest: false},
+		{name: "matching name in the wrong namespace", configMapName: "dhcp-leases", namespace: "some-other-namespace", expectRequest: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: tc.configMapName, Namespace: tc.namespace}}
+			requests := reconciler.leaseConfigMapToProvisioningRequest(context.TODO(), cm)
+			if tc.expectRequest {
+				assert.Len(t, requests, 1)
+				assert.Equal(t, metal3iov1alpha1.ProvisioningSingletonName, requests[0].Name)
+			} else {
+				assert.Empty(t, requests)
+			}
+		})
+	}
+}
+
+func TestProvisioning_Deletion(t *testing.T) {
+	now := metav1.Now()
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              metal3iov1alpha1.ProvisioningSingletonName,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{provisioningFinalizer},
+		},
+		Spec: metal3iov1alpha1.ProvisioningSpec{
+			ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged,
+		},
+	}
+	infra := &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status:     configv1.InfrastructureStatus{Platform: configv1.BareMetalPlatformType},
+	}
+
+	reconciler := newFakeProvisioningReconciler(config)
+	reconciler.OSClient = fakeconfigclientset.NewSimpleClientset(infra)
+
+	req := ctrl.Request{NamespacedName: client.ObjectKey{Name: metal3iov1alpha1.ProvisioningSingletonName}}
+	if _, err := reconciler.Reconcile(context.TODO(), req); err != nil {
+		t.Fatalf("unexpected error reconciling deletion: %v", err)
+	}
+
+	// provisioningFinalizer was the CR's only finalizer, so once it is
+	// removed the fake client, like a real apiserver, deletes the object.
+	got := &metal3iov1alpha1.Provisioning{}
+	err := reconciler.Client.Get(context.TODO(), client.ObjectKey{Name: metal3iov1alpha1.ProvisioningSingletonName}, got)
+	if err == nil {
+		assert.False(t, controllerutil.ContainsFinalizer(got, provisioningFinalizer), "finalizer should have been removed during deletion")
+	}
+	assert.True(t, apierrors.IsNotFound(err), "Provisioning CR should be gone once its finalizer is removed, got err = %v", err)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+// setConditionsForPhase updates the Provisioning CR's conditions to match
+// the given phase, using the same Available/Progressing/Degraded trio
+// that operators publish on their ClusterOperator.
+func setConditionsForPhase(config *metal3iov1alpha1.Provisioning, phase metal3iov1alpha1.ProvisioningPhase, reason, message string) {
+	config.Status.Phase = phase
+
+	available := operatorv1.ConditionFalse
+	progressing := operatorv1.ConditionFalse
+	degraded := operatorv1.ConditionFalse
+
+	switch phase {
+	case metal3iov1alpha1.PhasePending:
+		progressing = operatorv1.ConditionTrue
+	case metal3iov1alpha1.PhaseProvisioning:
+		progressing = operatorv1.ConditionTrue
+	case metal3iov1alpha1.PhaseReady, metal3iov1alpha1.PhaseDisabled:
+		available = operatorv1.ConditionTrue
+	case metal3iov1alpha1.PhaseDegraded:
+		degraded = operatorv1.ConditionTrue
+	}
+
+	setCondition(config, metal3iov1alpha1.AvailableCondition, available, reason, message)
+	setCondition(config, metal3iov1alpha1.ProgressingCondition, progressing, reason, message)
+	setCondition(config, metal3iov1alpha1.DegradedCondition, degraded, reason, message)
+}
+
+func setCondition(config *metal3iov1alpha1.Provisioning, conditionType operatorv1.OperatorStatusType, status operatorv1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	for i := range config.Status.Conditions {
+		cond := &config.Status.Conditions[i]
+		if cond.Type != conditionType {
+			continue
+		}
+		if cond.Status != status {
+			cond.Status = status
+			cond.LastTransitionTime = now
+		}
+		cond.Reason = reason
+		cond.Message = message
+		return
+	}
+	config.Status.Conditions = append(config.Status.Conditions, operatorv1.OperatorCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
+
+// updateProvisioningStatus persists the given phase/condition transition
+// on the Provisioning CR's status subresource.
+func (r *ProvisioningReconciler) updateProvisioningStatus(ctx context.Context, config *metal3iov1alpha1.Provisioning, phase metal3iov1alpha1.ProvisioningPhase, reason, message string) error {
+	setConditionsForPhase(config, phase, reason, message)
+	if err := r.Client.Status().Update(ctx, config); err != nil {
+		return fmt.Errorf("unable to update Provisioning status: %w", err)
+	}
+	return nil
+}
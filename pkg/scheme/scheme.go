@@ -0,0 +1,27 @@
+// Package scheme provides the single runtime.Scheme CBO registers all
+// of its API groups against, so that the manager, the reconcilers, and
+// the test suite all agree on the same set of known types.
+package scheme
+
+import (
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+// Scheme is the process-wide scheme used by the manager and by tests
+// that need a fake client with the same set of registered types.
+var Scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(configv1.AddToScheme(Scheme))
+	utilruntime.Must(metal3iov1alpha1.AddToScheme(Scheme))
+	utilruntime.Must(corev1.AddToScheme(Scheme))
+	utilruntime.Must(appsv1.AddToScheme(Scheme))
+	utilruntime.Must(monitoringv1.AddToScheme(Scheme))
+}
@@ -0,0 +1,35 @@
+// Package testutil collects helpers shared by CBO's controller tests.
+package testutil
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+	"github.com/openshift/cluster-baremetal-operator/pkg/scheme"
+)
+
+// deletionFinalizer is injected by NewFakeClientBuilder into any seed
+// object that already carries a DeletionTimestamp, since the fake
+// client panics when asked to track an object with a deletion
+// timestamp but no finalizers (a real apiserver would never produce
+// that combination).
+const deletionFinalizer = "testutil.cluster-baremetal-operator.openshift.io/fake-client"
+
+// NewFakeClientBuilder returns a fake client seeded with objs, using
+// CBO's shared scheme and with the status subresource enabled for
+// Provisioning so that status-only updates behave like they would
+// against a real apiserver.
+func NewFakeClientBuilder(objs ...client.Object) client.Client {
+	for _, obj := range objs {
+		if !obj.GetDeletionTimestamp().IsZero() && len(obj.GetFinalizers()) == 0 {
+			obj.SetFinalizers([]string{deletionFinalizer})
+		}
+	}
+
+	return fakeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithStatusSubresource(&metal3iov1alpha1.Provisioning{}).
+		WithObjects(objs...).
+		Build()
+}
@@ -0,0 +1,40 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+// TestNewFakeClientBuilderEnforcesStatusSubresource proves the reason
+// NewFakeClientBuilder enables WithStatusSubresource for Provisioning:
+// a plain Update() must not be able to change status, and only
+// Status().Update() may, matching a real apiserver.
+func TestNewFakeClientBuilderEnforcesStatusSubresource(t *testing.T) {
+	config := &metal3iov1alpha1.Provisioning{
+		ObjectMeta: metav1.ObjectMeta{Name: metal3iov1alpha1.ProvisioningSingletonName},
+		Spec:       metal3iov1alpha1.ProvisioningSpec{ProvisioningNetwork: metal3iov1alpha1.ProvisioningNetworkManaged},
+		Status:     metal3iov1alpha1.ProvisioningStatus{Phase: metal3iov1alpha1.PhasePending},
+	}
+	fakeClient := NewFakeClientBuilder(config)
+	key := client.ObjectKey{Name: metal3iov1alpha1.ProvisioningSingletonName}
+
+	config.Status.Phase = metal3iov1alpha1.PhaseReady
+	assert.NoError(t, fakeClient.Update(context.TODO(), config))
+
+	got := &metal3iov1alpha1.Provisioning{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), key, got))
+	assert.Equal(t, metal3iov1alpha1.PhasePending, got.Status.Phase, "plain Update must not persist a status change")
+
+	got.Status.Phase = metal3iov1alpha1.PhaseReady
+	assert.NoError(t, fakeClient.Status().Update(context.TODO(), got))
+
+	got = &metal3iov1alpha1.Provisioning{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), key, got))
+	assert.Equal(t, metal3iov1alpha1.PhaseReady, got.Status.Phase, "Status().Update() must persist a status change")
+}
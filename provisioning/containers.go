@@ -0,0 +1,50 @@
+package provisioning
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+const dnsmasqContainerName = "metal3-dnsmasq"
+
+// IronicAndDnsmasqContainers returns the containers that make up the
+// metal3/Ironic pod for the given Provisioning spec and network stack.
+// In Managed mode this includes a dnsmasq container configured with
+// the requested DHCP range; in Unmanaged and Disabled modes no
+// dnsmasq container is produced at all, since DHCP is either handled
+// out-of-band or not needed. stack, as detected from the CBO pod's own
+// status.podIPs, tells Ironic which IP family or families to listen on.
+func IronicAndDnsmasqContainers(spec metal3iov1alpha1.ProvisioningSpec, stack NetworkStackType) []corev1.Container {
+	containers := []corev1.Container{ironicContainer(spec, stack)}
+	if spec.ProvisioningNetwork == metal3iov1alpha1.ProvisioningNetworkManaged {
+		containers = append(containers, dnsmasqContainer(spec))
+	}
+	return containers
+}
+
+func ironicContainer(spec metal3iov1alpha1.ProvisioningSpec, stack NetworkStackType) corev1.Container {
+	return corev1.Container{
+		Name: "metal3-ironic",
+		Args: ironicArgs(spec, stack),
+	}
+}
+
+func ironicArgs(spec metal3iov1alpha1.ProvisioningSpec, stack NetworkStackType) []string {
+	args := []string{"--provisioning-interface", spec.ProvisioningInterface, "--ip-family", stack.String()}
+	if spec.ProvisioningNetwork == metal3iov1alpha1.ProvisioningNetworkUnmanaged {
+		args = append(args, "--dhcp-external")
+	}
+	return args
+}
+
+func dnsmasqContainer(spec metal3iov1alpha1.ProvisioningSpec) corev1.Container {
+	args := []string{"--interface", spec.ProvisioningInterface}
+	if spec.ProvisioningDHCPRange != "" {
+		args = append(args, "--dhcp-range", spec.ProvisioningDHCPRange)
+	}
+	return corev1.Container{
+		Name: dnsmasqContainerName,
+		Args: args,
+	}
+}
@@ -0,0 +1,59 @@
+package provisioning
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// ParseDHCPDLeases extracts MAC/IP pairs from the contents of an
+// ISC-dhcpd-style leases file, e.g. as found at
+// /var/lib/dhcp/dhcpd.leases on an externally-managed DHCP server.
+// Only the "hardware ethernet" and the lease's own address are used;
+// any other lease state (timestamps, client-id, etc.) is ignored.
+func ParseDHCPDLeases(contents string) ([]MACIPLease, error) {
+	var leases []MACIPLease
+	var currentIP string
+
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "lease "):
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("malformed lease line: %q", line)
+			}
+			currentIP = fields[1]
+		case strings.HasPrefix(line, "hardware ethernet"):
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("malformed hardware ethernet line: %q", line)
+			}
+			mac := strings.TrimSuffix(fields[2], ";")
+			if currentIP == "" {
+				return nil, fmt.Errorf("hardware ethernet line %q seen outside of a lease block", line)
+			}
+			leases = append(leases, MACIPLease{MACAddress: mac, IPAddress: currentIP})
+		case line == "}":
+			currentIP = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to parse DHCP leases: %w", err)
+	}
+	return leases, nil
+}
+
+// ParseLeaseConfigMapData extracts MAC/IP pairs from a ConfigMap's Data
+// map, where each key is a MAC address and each value is the leased IP
+// address. This is the simpler of the two supported lease sources and
+// is intended for operators who maintain leases by hand or via a small
+// sync job rather than point CBO at a dhcpd.leases file directly.
+func ParseLeaseConfigMapData(data map[string]string) []MACIPLease {
+	leases := make([]MACIPLease, 0, len(data))
+	for mac, ip := range data {
+		leases = append(leases, MACIPLease{MACAddress: mac, IPAddress: ip})
+	}
+	return leases
+}
@@ -0,0 +1,80 @@
+package provisioning
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDHCPDLeases(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contents    string
+		want        []MACIPLease
+		expectError bool
+	}{
+		{
+			name: "single lease",
+			contents: `lease 192.168.0.10 {
+  starts 1 2024/01/01 00:00:00;
+  hardware ethernet 52:54:00:aa:bb:cc;
+  binding state active;
+}
+`,
+			want: []MACIPLease{{MACAddress: "52:54:00:aa:bb:cc", IPAddress: "192.168.0.10"}},
+		},
+		{
+			name: "multiple leases",
+			contents: `lease 192.168.0.10 {
+  hardware ethernet 52:54:00:aa:bb:cc;
+}
+lease 192.168.0.11 {
+  hardware ethernet 52:54:00:dd:ee:ff;
+}
+`,
+			want: []MACIPLease{
+				{MACAddress: "52:54:00:aa:bb:cc", IPAddress: "192.168.0.10"},
+				{MACAddress: "52:54:00:dd:ee:ff", IPAddress: "192.168.0.11"},
+			},
+		},
+		{
+			name:     "no leases",
+			contents: "",
+			want:     nil,
+		},
+		{
+			name: "hardware ethernet outside of a lease block",
+			contents: `hardware ethernet 52:54:00:aa:bb:cc;
+`,
+			expectError: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseDHCPDLeases(tc.contents)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseLeaseConfigMapData(t *testing.T) {
+	data := map[string]string{
+		"52:54:00:aa:bb:cc": "192.168.0.10",
+		"52:54:00:dd:ee:ff": "192.168.0.11",
+	}
+
+	got := ParseLeaseConfigMapData(data)
+	sort.Slice(got, func(i, j int) bool { return got[i].MACAddress < got[j].MACAddress })
+
+	want := []MACIPLease{
+		{MACAddress: "52:54:00:aa:bb:cc", IPAddress: "192.168.0.10"},
+		{MACAddress: "52:54:00:dd:ee:ff", IPAddress: "192.168.0.11"},
+	}
+	assert.Equal(t, want, got)
+}
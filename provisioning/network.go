@@ -0,0 +1,79 @@
+package provisioning
+
+import (
+	"net"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NetworkStackType describes which IP families are present on the
+// provisioning network, as observed on the CBO pod itself.
+type NetworkStackType int
+
+const (
+	// NetworkStackV4 indicates only IPv4 addresses were found.
+	NetworkStackV4 NetworkStackType = iota
+	// NetworkStackV6 indicates only IPv6 addresses were found.
+	NetworkStackV6
+	// NetworkStackDual indicates both IPv4 and IPv6 addresses were found.
+	NetworkStackDual
+)
+
+// uniqueLocalBlock is the IPv6 Unique Local Address range (fc00::/7),
+// defined by RFC 4193. The net package does not expose an IsUniqueLocal
+// helper, so it is checked explicitly here alongside the loopback and
+// link-local checks the stdlib already provides.
+var uniqueLocalBlock = &net.IPNet{
+	IP:   net.ParseIP("fc00::"),
+	Mask: net.CIDRMask(7, 128),
+}
+
+// isIgnorableAddress reports whether ip should be excluded when
+// determining the provisioning network stack: loopback, link-local
+// (v4 or v6), and IPv6 ULA addresses are all out-of-band for the
+// purposes of deciding which global-scope families are routable.
+func isIgnorableAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || uniqueLocalBlock.Contains(ip)
+}
+
+// NetworkStackFromPodIPs determines the provisioning network stack from
+// the downward-API status.podIPs list, applying the same ignore rules
+// as networkStack while also filtering out IPv6 ULA addresses, which
+// don't appear on host network interfaces the way link-local addresses
+// do. It returns NetworkStackDual only when at least one global-scope
+// IPv4 address and one global-scope IPv6 address are present.
+func NetworkStackFromPodIPs(podIPs []corev1.PodIP) NetworkStackType {
+	var haveV4, haveV6 bool
+	for _, podIP := range podIPs {
+		ip := net.ParseIP(podIP.IP)
+		if ip == nil || isIgnorableAddress(ip) {
+			continue
+		}
+		if ip.To4() != nil {
+			haveV4 = true
+		} else {
+			haveV6 = true
+		}
+	}
+	switch {
+	case haveV4 && haveV6:
+		return NetworkStackDual
+	case haveV6:
+		return NetworkStackV6
+	default:
+		return NetworkStackV4
+	}
+}
+
+func (t NetworkStackType) String() string {
+	switch t {
+	case NetworkStackV4:
+		return "v4"
+	case NetworkStackV6:
+		return "v6"
+	case NetworkStackDual:
+		return "dual"
+	default:
+		return "unknown"
+	}
+}
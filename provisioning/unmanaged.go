@@ -0,0 +1,43 @@
+package provisioning
+
+import (
+	"fmt"
+
+	metal3iov1alpha1 "github.com/openshift/cluster-baremetal-operator/api/v1alpha1"
+)
+
+// ValidateUnmanagedConfig checks that a Provisioning spec using the
+// Unmanaged provisioning network mode is internally consistent: exactly
+// one external lease source must be configured, and the fields that
+// only make sense for CBO's own dnsmasq (Managed mode) must be unset.
+// ProvisioningDHCPRange is rejected for both Unmanaged and Disabled,
+// matching its own doc comment: CBO doesn't run dnsmasq in either mode.
+func ValidateUnmanagedConfig(spec metal3iov1alpha1.ProvisioningSpec) error {
+	if spec.ProvisioningDHCPRange != "" && spec.ProvisioningNetwork != metal3iov1alpha1.ProvisioningNetworkManaged {
+		return fmt.Errorf("provisioningDHCPRange must not be set when provisioningNetwork is %s", spec.ProvisioningNetwork)
+	}
+
+	if spec.ProvisioningNetwork != metal3iov1alpha1.ProvisioningNetworkUnmanaged {
+		return nil
+	}
+
+	haveConfigMap := spec.UnmanagedLeaseConfigMap != ""
+	haveLeaseFile := spec.UnmanagedLeaseFile != ""
+	switch {
+	case haveConfigMap && haveLeaseFile:
+		return fmt.Errorf("only one of unmanagedLeaseConfigMap or unmanagedLeaseFile may be set")
+	case !haveConfigMap && !haveLeaseFile:
+		return fmt.Errorf("unmanagedLeaseConfigMap or unmanagedLeaseFile is required when provisioningNetwork is Unmanaged")
+	}
+
+	return nil
+}
+
+// MACIPLease is a single MAC address to IP address mapping harvested
+// from an externally-managed DHCP lease source. It is used to
+// pre-populate BareMetalHost resources the same way the BPA-operator
+// ties MAC addresses to cluster membership.
+type MACIPLease struct {
+	MACAddress string
+	IPAddress  string
+}